@@ -0,0 +1,248 @@
+package main
+
+import (
+	"context"
+	"math/bits"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// board9 is a fast, allocation-light representation of a standard 9x9
+// Sudoku: the filled-in values plus per-row, per-column and per-box
+// bitmasks (bit n-1 set means digit n is already placed in that unit).
+// It backs the -all uniqueness-checking search, where the generic
+// Constraint-based Puzzle's slice copies and linear scans dominate
+// runtime.
+type board9 struct {
+	values                    [81]uint8
+	rowMask, colMask, boxMask [9]uint16
+}
+
+// newBoard9 builds a board9 from a flat 81-cell state.
+func newBoard9(state []uint8) *board9 {
+	b := &board9{}
+	for idx, v := range state {
+		if v != 0 {
+			b.place(idx, v)
+		}
+	}
+	return b
+}
+
+func boxOf(idx int) int {
+	r, c := idx/9, idx%9
+	return (r/3)*3 + c/3
+}
+
+// place sets cell idx to v and records it in the row/col/box masks.
+func (b *board9) place(idx int, v uint8) {
+	b.values[idx] = v
+	bit := uint16(1) << (v - 1)
+	r, c := idx/9, idx%9
+	b.rowMask[r] |= bit
+	b.colMask[c] |= bit
+	b.boxMask[boxOf(idx)] |= bit
+}
+
+// undo clears cell idx, which must currently hold v.
+func (b *board9) undo(idx int, v uint8) {
+	b.values[idx] = 0
+	bit := uint16(1) << (v - 1)
+	r, c := idx/9, idx%9
+	b.rowMask[r] &^= bit
+	b.colMask[c] &^= bit
+	b.boxMask[boxOf(idx)] &^= bit
+}
+
+// candidates returns the bitmask of digits idx could still take.
+func (b *board9) candidates(idx int) uint16 {
+	r, c := idx/9, idx%9
+	used := b.rowMask[r] | b.colMask[c] | b.boxMask[boxOf(idx)]
+	return ^used & 0x1FF
+}
+
+// bestCell returns the blank cell with the fewest remaining
+// candidates and its candidate mask. ok is false once the board is
+// complete.
+func (b *board9) bestCell() (idx int, mask uint16, ok bool) {
+	best := -1
+	bestCount := 10
+	var bestMask uint16
+
+	for i, v := range b.values {
+		if v != 0 {
+			continue
+		}
+
+		m := b.candidates(i)
+		count := bits.OnesCount16(m)
+		if count < bestCount {
+			best, bestCount, bestMask = i, count, m
+			if count == 0 {
+				break
+			}
+		}
+	}
+
+	if best == -1 {
+		return 0, 0, false
+	}
+
+	return best, bestMask, true
+}
+
+// clone copies the board, used when a worker needs to branch into an
+// independent search.
+func (b *board9) clone() *board9 {
+	c := *b
+	return &c
+}
+
+// isStandard9 reports whether p is a plain 9x9 puzzle with just the
+// row/column/box constraints, i.e. the case board9 was built for.
+func isStandard9(p *Puzzle) bool {
+	if p.Size != 9 || len(p.Constraints) != 27 {
+		return false
+	}
+
+	// a 9x9 jigsaw also has 27 constraints; only the standard boxes fit board9
+	for i, box := range boxConstraints(9, 3) {
+		if !sameInts(p.Constraints[18+i].Indices(), box.Indices()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// solveParallelBitmask is SolveParallel's fast path for standard 9x9
+// puzzles: it fans the first branch point out across a worker pool,
+// same as SolveParallel, but each worker searches with a board9,
+// mutating and undoing cells in place rather than cloning a Puzzle per
+// branch.
+func solveParallelBitmask(root *Puzzle, workers int, all bool) (solutions []*Puzzle, iterations int64) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	board := newBoard9(root.Cells)
+
+	idx, mask, ok := board.bestCell()
+	if !ok {
+		return []*Puzzle{stateToPuzzle(root.Size, board.values[:])}, 0
+	}
+
+	type branch struct {
+		idx int
+		val uint8
+	}
+
+	var branches []branch
+	for n := uint8(1); n <= 9; n++ {
+		if mask&(1<<(n-1)) != 0 {
+			branches = append(branches, branch{idx, n})
+		}
+	}
+
+	jobs := make(chan branch, len(branches))
+	for _, br := range branches {
+		jobs <- br
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for br := range jobs {
+				b := board.clone()
+				b.place(br.idx, br.val)
+
+				found := dfsBitmask(ctx, b, &iterations, all)
+				if len(found) == 0 {
+					continue
+				}
+
+				puzzles := make([]*Puzzle, len(found))
+				for i, state := range found {
+					puzzles[i] = stateToPuzzle(root.Size, state[:])
+				}
+
+				mu.Lock()
+				if all || len(solutions) == 0 {
+					solutions = append(solutions, puzzles...)
+				}
+				mu.Unlock()
+
+				if !all {
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return solutions, atomic.LoadInt64(&iterations)
+}
+
+// dfsBitmask runs a mutate-and-undo backtracking search over b,
+// returning early once ctx is cancelled by a sibling worker. When all
+// is false it stops at the first solution found.
+func dfsBitmask(ctx context.Context, b *board9, iterations *int64, all bool) [][81]uint8 {
+	var found [][81]uint8
+
+	var recurse func() bool
+	recurse = func() bool {
+		select {
+		case <-ctx.Done():
+			return true
+		default:
+		}
+
+		atomic.AddInt64(iterations, 1)
+
+		idx, mask, ok := b.bestCell()
+		if !ok {
+			found = append(found, b.values)
+			return !all
+		}
+
+		for n := uint8(1); n <= 9; n++ {
+			if mask&(1<<(n-1)) == 0 {
+				continue
+			}
+
+			b.place(idx, n)
+			stop := recurse()
+			b.undo(idx, n)
+
+			if stop {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	recurse()
+
+	return found
+}
+
+// stateToPuzzle wraps a flat cell slice as a standard-shaped Puzzle
+// for callers that expect SolveParallel's *Puzzle results.
+func stateToPuzzle(size int, cells []uint8) *Puzzle {
+	c := make([]uint8, len(cells))
+	copy(c, cells)
+	return &Puzzle{Size: size, Cells: c}
+}
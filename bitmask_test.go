@@ -0,0 +1,41 @@
+package main
+
+import "testing"
+
+// TestSolveParallelBitmaskUniqueSolution exercises the board9 fast
+// path (SolveParallel routes here whenever isStandard9 holds) via the
+// -all flag's uniqueness-checking mode.
+func TestSolveParallelBitmaskUniqueSolution(t *testing.T) {
+	puzzle, err := NewStandardPuzzle(9, append([]uint8(nil), classicPuzzle...))
+	if err != nil {
+		t.Fatalf("NewStandardPuzzle: %v", err)
+	}
+
+	solutions, _ := SolveParallel(puzzle, 2, true)
+	if len(solutions) != 1 {
+		t.Fatalf("got %d solutions, want 1", len(solutions))
+	}
+
+	for i, want := range classicSolution {
+		if solutions[0].Cells[i] != want {
+			t.Fatalf("cell %d = %d, want %d", i, solutions[0].Cells[i], want)
+		}
+	}
+}
+
+// TestSolveParallelCountsKnownTotal checks SolveParallel's -all
+// counting against a small, exhaustively-countable case: a blank 4x4
+// grid has exactly 288 distinct solutions. 4x4 doesn't route through
+// board9 (isStandard9 requires Size 9), so this guards the shared
+// SolveParallel/-all counting logic the bitmask path also relies on.
+func TestSolveParallelCountsKnownTotal(t *testing.T) {
+	puzzle, err := NewStandardPuzzle(4, make([]uint8, 16))
+	if err != nil {
+		t.Fatalf("NewStandardPuzzle: %v", err)
+	}
+
+	solutions, _ := SolveParallel(puzzle, 0, true)
+	if len(solutions) != 288 {
+		t.Fatalf("got %d solutions, want 288", len(solutions))
+	}
+}
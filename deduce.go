@@ -0,0 +1,613 @@
+package main
+
+import "sort"
+
+// deduce.go implements the human-style logical techniques a Sudoku
+// solver reaches for before resorting to search: naked/hidden singles,
+// naked/hidden pairs and triples, locked candidates (pointing and
+// claiming), and X-Wing. It only applies to standard 9x9 puzzles,
+// since the techniques below assume exactly nine rows, columns, boxes
+// and digits.
+
+// candidateGrid tracks, per cell, which of the nine digits remain
+// possible. Filled cells have no candidates set.
+type candidateGrid [81][9]bool
+
+// DeduceResult is the outcome of running Deduce: the state advanced as
+// far as pure logic allows, the techniques that fired along the way,
+// and a difficulty rating derived from the hardest technique used.
+type DeduceResult struct {
+	State      []uint8
+	Techniques []string
+	Difficulty string
+}
+
+// Deduce repeatedly applies human solving techniques to state until
+// none of them make further progress. Whatever it cannot resolve is
+// left as 0 for the search (expand/SolveDLX) to finish off.
+func Deduce(state []uint8) *DeduceResult {
+	s := make([]uint8, len(state))
+	copy(s, state)
+
+	used := map[string]bool{}
+
+	for {
+		candidates := buildCandidates(s)
+
+		// Run every elimination technique to a fixed point for this
+		// round before looking for a single: a naked pair often just
+		// narrows other cells without immediately creating one, and a
+		// later technique (e.g. X-Wing) may only apply once an earlier
+		// one has already pruned candidates.
+		for {
+			progressed := false
+			if applyLockedCandidates(candidates) {
+				used["locked candidates"] = true
+				progressed = true
+			}
+			if applyNakedSubsets(candidates, 2) {
+				used["naked pair"] = true
+				progressed = true
+			}
+			if applyNakedSubsets(candidates, 3) {
+				used["naked triple"] = true
+				progressed = true
+			}
+			if applyHiddenSubsets(candidates, 2) {
+				used["hidden pair"] = true
+				progressed = true
+			}
+			if applyHiddenSubsets(candidates, 3) {
+				used["hidden triple"] = true
+				progressed = true
+			}
+			if applyXWing(candidates) {
+				used["x-wing"] = true
+				progressed = true
+			}
+			if !progressed {
+				break
+			}
+		}
+
+		if placeNakedSingles(s, candidates) {
+			used["naked single"] = true
+			continue
+		}
+
+		if placeHiddenSingles(s, candidates) {
+			used["hidden single"] = true
+			continue
+		}
+
+		// Eliminations reached a fixed point against the unchanged
+		// state and placed nothing: rebuilding candidates next round
+		// would reproduce exactly this, so stop here.
+		break
+	}
+
+	techniques := make([]string, 0, len(used))
+	for t := range used {
+		techniques = append(techniques, t)
+	}
+	sort.Strings(techniques)
+
+	return &DeduceResult{
+		State:      s,
+		Techniques: techniques,
+		Difficulty: rate(techniques),
+	}
+}
+
+// rate grades a puzzle by the hardest technique that fired during
+// Deduce. Deduce runs every elimination technique before looking for
+// singles, so a technique can fire, and raise the grade, on a puzzle
+// that singles alone would solve.
+func rate(techniques []string) string {
+	switch {
+	case containsTechnique(techniques, "x-wing"):
+		return "hard"
+	case containsTechnique(techniques, "naked triple"), containsTechnique(techniques, "hidden triple"),
+		containsTechnique(techniques, "locked candidates"):
+		return "medium"
+	case containsTechnique(techniques, "naked pair"), containsTechnique(techniques, "hidden pair"):
+		return "medium"
+	case containsTechnique(techniques, "hidden single"), containsTechnique(techniques, "naked single"):
+		return "easy"
+	default:
+		return "trivial"
+	}
+}
+
+func containsTechnique(techniques []string, t string) bool {
+	for _, candidate := range techniques {
+		if candidate == t {
+			return true
+		}
+	}
+	return false
+}
+
+// units returns the 27 groups of 9 cell indices (rows, columns, boxes)
+// that must each hold distinct digits in a standard 9x9 puzzle.
+func units() [][9]int {
+	var u [][9]int
+
+	for r := 0; r < 9; r++ {
+		var row [9]int
+		for c := 0; c < 9; c++ {
+			row[c] = r*9 + c
+		}
+		u = append(u, row)
+	}
+
+	for c := 0; c < 9; c++ {
+		var col [9]int
+		for r := 0; r < 9; r++ {
+			col[r] = r*9 + c
+		}
+		u = append(u, col)
+	}
+
+	for br := 0; br < 3; br++ {
+		for bc := 0; bc < 3; bc++ {
+			u = append(u, boxUnit(br, bc))
+		}
+	}
+
+	return u
+}
+
+func boxUnit(br, bc int) [9]int {
+	var box [9]int
+	k := 0
+	for i := 0; i < 3; i++ {
+		for j := 0; j < 3; j++ {
+			box[k] = (br*3+i)*9 + (bc*3 + j)
+			k++
+		}
+	}
+	return box
+}
+
+func inBox(idx, br, bc int) bool {
+	r, c := idx/9, idx%9
+	return r/3 == br && c/3 == bc
+}
+
+// buildCandidates derives the candidate grid implied by state alone,
+// with no eliminations from the higher-order techniques applied yet.
+func buildCandidates(state []uint8) *candidateGrid {
+	var cg candidateGrid
+
+	for idx, val := range state {
+		if val == 0 {
+			for n := 0; n < 9; n++ {
+				cg[idx][n] = true
+			}
+		}
+	}
+
+	for _, u := range units() {
+		var used [9]bool
+		for _, idx := range u {
+			if v := state[idx]; v != 0 {
+				used[v-1] = true
+			}
+		}
+		for _, idx := range u {
+			if state[idx] != 0 {
+				continue
+			}
+			for n := 0; n < 9; n++ {
+				if used[n] {
+					cg[idx][n] = false
+				}
+			}
+		}
+	}
+
+	return &cg
+}
+
+func cellCandidateCount(cg *candidateGrid, idx int) int {
+	count := 0
+	for n := 0; n < 9; n++ {
+		if cg[idx][n] {
+			count++
+		}
+	}
+	return count
+}
+
+// placeNakedSingles fills in any blank cell left with exactly one
+// candidate.
+func placeNakedSingles(state []uint8, cg *candidateGrid) bool {
+	placed := false
+
+	for idx, val := range state {
+		if val != 0 {
+			continue
+		}
+
+		count, last := 0, -1
+		for n := 0; n < 9; n++ {
+			if cg[idx][n] {
+				count++
+				last = n
+			}
+		}
+
+		if count == 1 {
+			state[idx] = uint8(last + 1)
+			placed = true
+		}
+	}
+
+	return placed
+}
+
+// placeHiddenSingles fills in any blank cell that is the only place in
+// one of its units a given digit can still go.
+func placeHiddenSingles(state []uint8, cg *candidateGrid) bool {
+	placed := false
+
+	for _, u := range units() {
+		for n := 0; n < 9; n++ {
+			count, last := 0, -1
+			for _, idx := range u {
+				if state[idx] != 0 {
+					continue
+				}
+				if cg[idx][n] {
+					count++
+					last = idx
+				}
+			}
+
+			if count == 1 && state[last] == 0 {
+				state[last] = uint8(n + 1)
+				placed = true
+			}
+		}
+	}
+
+	return placed
+}
+
+// applyLockedCandidates removes candidates via pointing (a digit
+// confined to one row/column within a box also disappears from the
+// rest of that row/column) and claiming (a digit confined to one box
+// within a row/column also disappears from the rest of that box).
+func applyLockedCandidates(cg *candidateGrid) bool {
+	changed := false
+
+	for br := 0; br < 3; br++ {
+		for bc := 0; bc < 3; bc++ {
+			box := boxUnit(br, bc)
+
+			for n := 0; n < 9; n++ {
+				rows := map[int]bool{}
+				cols := map[int]bool{}
+
+				for _, idx := range box {
+					if cg[idx][n] {
+						rows[idx/9] = true
+						cols[idx%9] = true
+					}
+				}
+
+				if len(rows) == 1 {
+					for r := range rows {
+						for c := 0; c < 9; c++ {
+							idx := r*9 + c
+							if inBox(idx, br, bc) {
+								continue
+							}
+							if cg[idx][n] {
+								cg[idx][n] = false
+								changed = true
+							}
+						}
+					}
+				}
+
+				if len(cols) == 1 {
+					for c := range cols {
+						for r := 0; r < 9; r++ {
+							idx := r*9 + c
+							if inBox(idx, br, bc) {
+								continue
+							}
+							if cg[idx][n] {
+								cg[idx][n] = false
+								changed = true
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	for r := 0; r < 9; r++ {
+		changed = claimInUnit(cg, rowUnit(r)) || changed
+	}
+	for c := 0; c < 9; c++ {
+		changed = claimInUnit(cg, colUnit(c)) || changed
+	}
+
+	return changed
+}
+
+func rowUnit(r int) [9]int {
+	var row [9]int
+	for c := 0; c < 9; c++ {
+		row[c] = r*9 + c
+	}
+	return row
+}
+
+func colUnit(c int) [9]int {
+	var col [9]int
+	for r := 0; r < 9; r++ {
+		col[r] = r*9 + c
+	}
+	return col
+}
+
+// claimInUnit handles the "claiming" half of locked candidates: if a
+// digit's candidates within a row/column all fall in a single box,
+// eliminate it from the rest of that box.
+func claimInUnit(cg *candidateGrid, unit [9]int) bool {
+	changed := false
+
+	for n := 0; n < 9; n++ {
+		boxes := map[[2]int]bool{}
+		for _, idx := range unit {
+			if cg[idx][n] {
+				r, c := idx/9, idx%9
+				boxes[[2]int{r / 3, c / 3}] = true
+			}
+		}
+
+		if len(boxes) != 1 {
+			continue
+		}
+
+		inUnit := map[int]bool{}
+		for _, idx := range unit {
+			inUnit[idx] = true
+		}
+
+		for b := range boxes {
+			for _, idx := range boxUnit(b[0], b[1]) {
+				if inUnit[idx] {
+					continue
+				}
+				if cg[idx][n] {
+					cg[idx][n] = false
+					changed = true
+				}
+			}
+		}
+	}
+
+	return changed
+}
+
+// applyNakedSubsets finds k cells in a unit whose combined candidates
+// total exactly k digits, and eliminates those digits from the rest of
+// the unit.
+func applyNakedSubsets(cg *candidateGrid, k int) bool {
+	changed := false
+
+	for _, u := range units() {
+		var cells []int
+		for _, idx := range u {
+			if n := cellCandidateCount(cg, idx); n >= 2 && n <= k {
+				cells = append(cells, idx)
+			}
+		}
+
+		for _, combo := range combinations(cells, k) {
+			var union [9]bool
+			for _, idx := range combo {
+				for n := 0; n < 9; n++ {
+					if cg[idx][n] {
+						union[n] = true
+					}
+				}
+			}
+
+			unionCount := 0
+			for _, v := range union {
+				if v {
+					unionCount++
+				}
+			}
+			if unionCount != k {
+				continue
+			}
+
+			inCombo := map[int]bool{}
+			for _, idx := range combo {
+				inCombo[idx] = true
+			}
+
+			for _, idx := range u {
+				if inCombo[idx] {
+					continue
+				}
+				for n := 0; n < 9; n++ {
+					if union[n] && cg[idx][n] {
+						cg[idx][n] = false
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	return changed
+}
+
+// applyHiddenSubsets finds k digits in a unit confined to exactly k
+// cells between them, and strips every other candidate from those cells.
+func applyHiddenSubsets(cg *candidateGrid, k int) bool {
+	changed := false
+
+	for _, u := range units() {
+		var digits []int
+		for n := 0; n < 9; n++ {
+			count := 0
+			for _, idx := range u {
+				if cg[idx][n] {
+					count++
+				}
+			}
+			if count >= 2 && count <= k {
+				digits = append(digits, n)
+			}
+		}
+
+		for _, combo := range combinations(digits, k) {
+			cellSet := map[int]bool{}
+			for _, idx := range u {
+				for _, n := range combo {
+					if cg[idx][n] {
+						cellSet[idx] = true
+					}
+				}
+			}
+
+			if len(cellSet) != k {
+				continue
+			}
+
+			inCombo := map[int]bool{}
+			for _, n := range combo {
+				inCombo[n] = true
+			}
+
+			for idx := range cellSet {
+				for n := 0; n < 9; n++ {
+					if !inCombo[n] && cg[idx][n] {
+						cg[idx][n] = false
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	return changed
+}
+
+// applyXWing eliminates a digit from a row/column pair when it is
+// confined to the same two columns/rows in two different rows/columns.
+func applyXWing(cg *candidateGrid) bool {
+	changed := false
+
+	for n := 0; n < 9; n++ {
+		changed = xWingPass(cg, n, true) || changed
+		changed = xWingPass(cg, n, false) || changed
+	}
+
+	return changed
+}
+
+// xWingPass runs one direction of X-Wing: byRow scans rows for a
+// shared column pair and eliminates down those columns; !byRow is the
+// transposed scan over columns.
+func xWingPass(cg *candidateGrid, n int, byRow bool) bool {
+	changed := false
+
+	lines := map[int][]int{}
+	for i := 0; i < 9; i++ {
+		var positions []int
+		for j := 0; j < 9; j++ {
+			idx := lineIndex(i, j, byRow)
+			if cg[idx][n] {
+				positions = append(positions, j)
+			}
+		}
+		if len(positions) == 2 {
+			lines[i] = positions
+		}
+	}
+
+	keys := make([]int, 0, len(lines))
+	for i := range lines {
+		keys = append(keys, i)
+	}
+	sort.Ints(keys)
+
+	for a := 0; a < len(keys); a++ {
+		for b := a + 1; b < len(keys); b++ {
+			i1, i2 := keys[a], keys[b]
+			if !sameInts(lines[i1], lines[i2]) {
+				continue
+			}
+
+			for _, j := range lines[i1] {
+				for i := 0; i < 9; i++ {
+					if i == i1 || i == i2 {
+						continue
+					}
+					idx := lineIndex(i, j, byRow)
+					if cg[idx][n] {
+						cg[idx][n] = false
+						changed = true
+					}
+				}
+			}
+		}
+	}
+
+	return changed
+}
+
+func lineIndex(i, j int, byRow bool) int {
+	if byRow {
+		return i*9 + j
+	}
+	return j*9 + i
+}
+
+func sameInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// combinations returns every k-length subset of items, preserving order.
+func combinations(items []int, k int) [][]int {
+	var result [][]int
+	if k <= 0 || k > len(items) {
+		return result
+	}
+
+	var pick func(start int, chosen []int)
+	pick = func(start int, chosen []int) {
+		if len(chosen) == k {
+			combo := make([]int, k)
+			copy(combo, chosen)
+			result = append(result, combo)
+			return
+		}
+		for i := start; i < len(items); i++ {
+			pick(i+1, append(chosen, items[i]))
+		}
+	}
+
+	pick(0, nil)
+
+	return result
+}
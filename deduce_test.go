@@ -0,0 +1,78 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDeduceSolvesClassicPuzzle(t *testing.T) {
+	result := Deduce(classicPuzzle)
+
+	solved, err := SolveDLX(result.State)
+	if err != nil {
+		t.Fatalf("SolveDLX on deduced state failed: %v", err)
+	}
+
+	for i, want := range classicSolution {
+		if solved[i] != want {
+			t.Fatalf("cell %d = %d, want %d", i, solved[i], want)
+		}
+	}
+}
+
+// TestDeduceTerminates guards against a past bug where an elimination
+// technique (locked candidates, naked/hidden subsets, X-Wing) could
+// narrow candidates without producing a single in the same round, so
+// the round-tracking never recognised a fixed point and looped
+// forever. This puzzle exercises naked pairs and triples, hidden
+// triples and locked candidates before any single is found.
+func TestDeduceTerminates(t *testing.T) {
+	state := []uint8{
+		5, 0, 0, 0, 7, 8, 9, 0, 0,
+		0, 0, 0, 1, 0, 5, 0, 0, 0,
+		0, 9, 8, 0, 4, 2, 5, 0, 0,
+		8, 0, 0, 7, 6, 0, 4, 0, 0,
+		4, 0, 0, 0, 0, 0, 0, 9, 1,
+		0, 0, 0, 9, 2, 0, 0, 5, 6,
+		0, 0, 0, 5, 0, 0, 0, 0, 4,
+		0, 0, 0, 4, 0, 9, 6, 0, 5,
+		3, 4, 5, 2, 8, 6, 1, 0, 0,
+	}
+
+	done := make(chan *DeduceResult, 1)
+	go func() { done <- Deduce(state) }()
+
+	select {
+	case result := <-done:
+		solved, err := SolveDLX(result.State)
+		if err != nil {
+			t.Fatalf("SolveDLX on deduced state failed: %v", err)
+		}
+
+		for i, given := range state {
+			if given != 0 && solved[i] != given {
+				t.Fatalf("cell %d = %d, does not match given %d", i, solved[i], given)
+			}
+		}
+
+		if !isValidSolved(solved) {
+			t.Fatalf("solved grid violates row/column/box uniqueness: %v", solved)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Deduce did not terminate")
+	}
+}
+
+func isValidSolved(state []uint8) bool {
+	for _, u := range units() {
+		var seen [9]bool
+		for _, idx := range u {
+			v := state[idx]
+			if v == 0 || seen[v-1] {
+				return false
+			}
+			seen[v-1] = true
+		}
+	}
+	return true
+}
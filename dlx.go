@@ -0,0 +1,224 @@
+package main
+
+import "errors"
+
+// dlx implements Knuth's Algorithm X over a toroidal doubly-linked
+// list ("dancing links"), used to solve standard 9x9 Sudoku by
+// reduction to exact cover.
+//
+// The exact-cover matrix has 324 columns: 81 "cell (r,c) is filled",
+// 81 "row r holds digit n", 81 "column c holds digit n" and 81
+// "box b holds digit n". Each candidate placement (r, c, n) is a row
+// covering exactly those four columns.
+
+const (
+	dlxSize    = 9
+	dlxBoxSize = 3
+	dlxCols    = dlxSize * dlxSize * 4
+)
+
+// dlxNode is one cell of the toroidal linked list: either a column
+// header or a 1-entry in the matrix. column always points at the
+// owning header, including for header nodes themselves.
+type dlxNode struct {
+	left, right, up, down *dlxNode
+	column                *dlxColumn
+	row                   int // candidate row id this node belongs to; unused on headers
+}
+
+// dlxColumn is a column header, tracking how many rows currently cover it.
+type dlxColumn struct {
+	dlxNode
+	size int
+	name int
+}
+
+// dlxMatrix is the sparse exact-cover matrix plus the root header.
+type dlxMatrix struct {
+	head    *dlxNode
+	columns []*dlxColumn
+}
+
+// newDLXMatrix builds the 729x324 standard Sudoku exact-cover matrix,
+// keeping only the given digit as a candidate for each given cell in
+// state; blank cells keep all nine digits.
+func newDLXMatrix(state []uint8) *dlxMatrix {
+	m := &dlxMatrix{head: &dlxNode{}}
+	m.head.left = m.head
+	m.head.right = m.head
+
+	m.columns = make([]*dlxColumn, dlxCols)
+	for i := 0; i < dlxCols; i++ {
+		col := &dlxColumn{name: i}
+		col.column = col
+		col.up = &col.dlxNode
+		col.down = &col.dlxNode
+		m.appendColumn(col)
+		m.columns[i] = col
+	}
+
+	for r := 0; r < dlxSize; r++ {
+		for c := 0; c < dlxSize; c++ {
+			given := state[r*dlxSize+c]
+
+			for n := 1; n <= dlxSize; n++ {
+				if given != 0 && int(given) != n {
+					continue
+				}
+
+				rowID := r*dlxSize*dlxSize + c*dlxSize + (n - 1)
+				m.addCandidateRow(rowID, r, c, n)
+			}
+		}
+	}
+
+	return m
+}
+
+func (m *dlxMatrix) appendColumn(col *dlxColumn) {
+	col.right = m.head
+	col.left = m.head.left
+	m.head.left.right = &col.dlxNode
+	m.head.left = &col.dlxNode
+}
+
+func boxIndex(r, c int) int {
+	return (r/dlxBoxSize)*dlxBoxSize + c/dlxBoxSize
+}
+
+// addCandidateRow links one candidate placement (r, c, n) into its
+// four covering columns.
+func (m *dlxMatrix) addCandidateRow(rowID, r, c, n int) {
+	cellCol := r*dlxSize + c
+	rowCol := dlxSize*dlxSize + r*dlxSize + (n - 1)
+	colCol := 2*dlxSize*dlxSize + c*dlxSize + (n - 1)
+	boxCol := 3*dlxSize*dlxSize + boxIndex(r, c)*dlxSize + (n - 1)
+
+	nodes := make([]*dlxNode, 0, 4)
+	for _, colIdx := range []int{cellCol, rowCol, colCol, boxCol} {
+		col := m.columns[colIdx]
+		node := &dlxNode{column: col, row: rowID}
+
+		node.up = col.up
+		node.down = &col.dlxNode
+		col.up.down = node
+		col.up = node
+		col.size++
+
+		nodes = append(nodes, node)
+	}
+
+	for i, n := range nodes {
+		n.right = nodes[(i+1)%len(nodes)]
+		n.left = nodes[(i-1+len(nodes))%len(nodes)]
+	}
+}
+
+// cover removes col from the header list and removes every row that
+// intersects it from every other column it touches.
+func (m *dlxMatrix) cover(col *dlxColumn) {
+	col.right.left = col.left
+	col.left.right = col.right
+
+	for row := col.down; row != &col.dlxNode; row = row.down {
+		for node := row.right; node != row; node = node.right {
+			node.down.up = node.up
+			node.up.down = node.down
+			node.column.size--
+		}
+	}
+}
+
+// uncover reverses a prior cover, in strict reverse order.
+func (m *dlxMatrix) uncover(col *dlxColumn) {
+	for row := col.up; row != &col.dlxNode; row = row.up {
+		for node := row.left; node != row; node = node.left {
+			node.column.size++
+			node.down.up = node
+			node.up.down = node
+		}
+	}
+
+	col.right.left = &col.dlxNode
+	col.left.right = &col.dlxNode
+}
+
+// chooseColumn returns the uncovered column with the fewest remaining
+// rows (the "S heuristic"), to keep branching factor low.
+func (m *dlxMatrix) chooseColumn() *dlxColumn {
+	var best *dlxColumn
+
+	for node := m.head.right; node != m.head; node = node.right {
+		if best == nil || node.column.size < best.size {
+			best = node.column
+		}
+	}
+
+	return best
+}
+
+// search performs Algorithm X, recording chosen row ids in solution.
+// It returns the accumulated solution and true as soon as every
+// column has been covered.
+func (m *dlxMatrix) search(solution []int) ([]int, bool) {
+	if m.head.right == m.head {
+		return solution, true
+	}
+
+	col := m.chooseColumn()
+	if col.size == 0 {
+		return nil, false
+	}
+
+	m.cover(col)
+
+	for row := col.down; row != &col.dlxNode; row = row.down {
+		solution = append(solution, row.row)
+
+		for node := row.right; node != row; node = node.right {
+			m.cover(node.column)
+		}
+
+		if result, ok := m.search(solution); ok {
+			return result, true
+		}
+
+		for node := row.left; node != row; node = node.left {
+			m.uncover(node.column)
+		}
+
+		solution = solution[:len(solution)-1]
+	}
+
+	m.uncover(col)
+
+	return nil, false
+}
+
+// SolveDLX solves a standard 9x9 Sudoku by reduction to exact cover
+// using Knuth's Dancing Links (Algorithm X), returning the solved
+// 81-cell state. It leaves the existing comma-separated -state CLI
+// input untouched; this is an additional, much faster solve path used
+// for standard puzzles.
+func SolveDLX(state []uint8) ([]uint8, error) {
+	if len(state) != dlxSize*dlxSize {
+		return nil, errors.New("state is not the right size")
+	}
+
+	m := newDLXMatrix(state)
+
+	solution, ok := m.search(nil)
+	if !ok {
+		return nil, errors.New("no solution found")
+	}
+
+	result := make([]uint8, dlxSize*dlxSize)
+	for _, rowID := range solution {
+		r := rowID / (dlxSize * dlxSize)
+		c := (rowID / dlxSize) % dlxSize
+		n := rowID%dlxSize + 1
+		result[r*dlxSize+c] = uint8(n)
+	}
+
+	return result, nil
+}
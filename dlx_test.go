@@ -0,0 +1,46 @@
+package main
+
+import "testing"
+
+var classicPuzzle = []uint8{
+	5, 3, 0, 0, 7, 0, 0, 0, 0,
+	6, 0, 0, 1, 9, 5, 0, 0, 0,
+	0, 9, 8, 0, 0, 0, 0, 6, 0,
+	8, 0, 0, 0, 6, 0, 0, 0, 3,
+	4, 0, 0, 8, 0, 3, 0, 0, 1,
+	7, 0, 0, 0, 2, 0, 0, 0, 6,
+	0, 6, 0, 0, 0, 0, 2, 8, 0,
+	0, 0, 0, 4, 1, 9, 0, 0, 5,
+	0, 0, 0, 0, 8, 0, 0, 7, 9,
+}
+
+var classicSolution = []uint8{
+	5, 3, 4, 6, 7, 8, 9, 1, 2,
+	6, 7, 2, 1, 9, 5, 3, 4, 8,
+	1, 9, 8, 3, 4, 2, 5, 6, 7,
+	8, 5, 9, 7, 6, 1, 4, 2, 3,
+	4, 2, 6, 8, 5, 3, 7, 9, 1,
+	7, 1, 3, 9, 2, 4, 8, 5, 6,
+	9, 6, 1, 5, 3, 7, 2, 8, 4,
+	2, 8, 7, 4, 1, 9, 6, 3, 5,
+	3, 4, 5, 2, 8, 6, 1, 7, 9,
+}
+
+func TestSolveDLX(t *testing.T) {
+	got, err := SolveDLX(classicPuzzle)
+	if err != nil {
+		t.Fatalf("SolveDLX returned an error: %v", err)
+	}
+
+	for i, want := range classicSolution {
+		if got[i] != want {
+			t.Fatalf("cell %d = %d, want %d", i, got[i], want)
+		}
+	}
+}
+
+func TestSolveDLXWrongSize(t *testing.T) {
+	if _, err := SolveDLX(make([]uint8, 10)); err == nil {
+		t.Fatal("expected an error for a state of the wrong size")
+	}
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// getInitialState parses state into a size*size sudoku state, in
+// whichever of the supported formats is named by format:
+//
+//   - csv:  the original comma-separated list of cell values
+//   - line: the conventional single-line form, 81 characters long,
+//     using '.' or '0' for blanks (as in published puzzle corpora)
+//   - grid: a multi-line ASCII grid with '|'/'-' separators; any
+//     character that isn't a digit or '.' is ignored
+//
+// line and grid both read one character per cell and expect size*size
+// cells (81 for the classic 9x9 layout), so values above 9 cannot be
+// written in them.
+func getInitialState(state string, size int, format string) ([]uint8, error) {
+	switch format {
+	case "csv":
+		return parseCSV(state, size)
+	case "line", "grid":
+		return parseLine(state, size)
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func parseCSV(state string, size int) ([]uint8, error) {
+	cells := strings.Split(state, ",")
+	if len(cells) != size*size {
+		return nil, errors.New("could not parse state")
+	}
+
+	initialState := make([]uint8, 0, size*size)
+	for i := 0; i < size*size; i++ {
+		val, err := strconv.ParseInt(cells[i], 10, 16)
+		if err != nil || val < 0 || int(val) > size {
+			return nil, fmt.Errorf("cell %d: %q is not a value between 0 and %d", i, cells[i], size)
+		}
+
+		initialState = append(initialState, uint8(val))
+	}
+
+	return initialState, nil
+}
+
+// parseLine extracts every digit or '.' character from state, in
+// order, treating '.' and '0' both as blank. It underlies both the
+// 81-char line format and the multi-line grid format, since a grid is
+// just a line format with decorative separators sprinkled in.
+func parseLine(state string, size int) ([]uint8, error) {
+	initialState := make([]uint8, 0, size*size)
+
+	for _, r := range state {
+		switch {
+		case r == '.':
+			initialState = append(initialState, 0)
+		case r >= '0' && r <= '9':
+			initialState = append(initialState, uint8(r-'0'))
+		}
+	}
+
+	if len(initialState) != size*size {
+		return nil, errors.New("could not parse state")
+	}
+
+	for i, v := range initialState {
+		if int(v) > size {
+			return nil, fmt.Errorf("cell %d: %d is not a value between 0 and %d", i, v, size)
+		}
+	}
+
+	return initialState, nil
+}
+
+// formatState renders cells back out in the given format.
+func formatState(cells []uint8, format string) (string, error) {
+	switch format {
+	case "csv":
+		return formatCSV(cells), nil
+	case "line":
+		return formatLine(cells), nil
+	default:
+		return "", fmt.Errorf("unknown format %q", format)
+	}
+}
+
+func formatCSV(cells []uint8) string {
+	parts := make([]string, len(cells))
+	for i, v := range cells {
+		parts[i] = strconv.Itoa(int(v))
+	}
+	return strings.Join(parts, ",")
+}
+
+func formatLine(cells []uint8) string {
+	var sb strings.Builder
+	for _, v := range cells {
+		if v == 0 {
+			sb.WriteByte('.')
+		} else {
+			sb.WriteByte('0' + v)
+		}
+	}
+	return sb.String()
+}
+
+// readSDM reads an SDM file: one puzzle per line, each in the 81-char
+// line format, for batch solving against published puzzle corpora.
+func readSDM(path string, size int) ([][]uint8, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var puzzles [][]uint8
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		state, err := parseLine(line, size)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse line %q: %w", line, err)
+		}
+
+		puzzles = append(puzzles, state)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return puzzles, nil
+}
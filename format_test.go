@@ -0,0 +1,109 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetInitialStateCSV(t *testing.T) {
+	got, err := getInitialState(formatCSV(classicPuzzle), 9, "csv")
+	if err != nil {
+		t.Fatalf("getInitialState: %v", err)
+	}
+
+	for i, want := range classicPuzzle {
+		if got[i] != want {
+			t.Fatalf("cell %d = %d, want %d", i, got[i], want)
+		}
+	}
+}
+
+func TestGetInitialStateLine(t *testing.T) {
+	got, err := getInitialState(formatLine(classicPuzzle), 9, "line")
+	if err != nil {
+		t.Fatalf("getInitialState: %v", err)
+	}
+
+	for i, want := range classicPuzzle {
+		if got[i] != want {
+			t.Fatalf("cell %d = %d, want %d", i, got[i], want)
+		}
+	}
+}
+
+// TestGetInitialStateGrid checks that decorative separators around the
+// line format are ignored, as the format doc comment promises.
+func TestGetInitialStateGrid(t *testing.T) {
+	grid := "53.|.7.|...\n" +
+		"6..|195|...\n" +
+		".98|...|.6.\n" +
+		"---+---+---\n" +
+		"8..|.6.|..3\n" +
+		"4..|8.3|..1\n" +
+		"7..|.2.|..6\n" +
+		"---+---+---\n" +
+		".6.|...|28.\n" +
+		"...|419|..5\n" +
+		"...|.8.|.79\n"
+
+	got, err := getInitialState(grid, 9, "grid")
+	if err != nil {
+		t.Fatalf("getInitialState: %v", err)
+	}
+
+	for i, want := range classicPuzzle {
+		if got[i] != want {
+			t.Fatalf("cell %d = %d, want %d", i, got[i], want)
+		}
+	}
+}
+
+func TestReadSDM(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "puzzles.sdm")
+	contents := formatLine(classicPuzzle) + "\n" + formatLine(classicPuzzle) + "\n"
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	puzzles, err := readSDM(path, 9)
+	if err != nil {
+		t.Fatalf("readSDM: %v", err)
+	}
+
+	if len(puzzles) != 2 {
+		t.Fatalf("got %d puzzles, want 2", len(puzzles))
+	}
+
+	for i, want := range classicPuzzle {
+		if puzzles[0][i] != want || puzzles[1][i] != want {
+			t.Fatalf("cell %d does not round-trip through readSDM", i)
+		}
+	}
+}
+
+// TestGetInitialStateRejectsOutOfRangeValue guards against a past bug
+// where a cell value above the puzzle size (e.g. a CSV typo like "99")
+// indexed an internal [9]bool array out of range deep inside Deduce
+// instead of being rejected at the parsing boundary.
+func TestGetInitialStateRejectsOutOfRangeValue(t *testing.T) {
+	cells := make([]string, 81)
+	for i := range cells {
+		cells[i] = "0"
+	}
+	cells[0] = "99"
+
+	state := cells[0]
+	for _, c := range cells[1:] {
+		state += "," + c
+	}
+
+	if _, err := getInitialState(state, 9, "csv"); err == nil {
+		t.Fatal("expected an error for an out-of-range CSV cell value")
+	}
+
+	// a digit of 9 is a valid single character but exceeds a 4x4 grid's size
+	if _, err := getInitialState("9...............", 4, "line"); err == nil {
+		t.Fatal("expected an error for an out-of-range line cell value")
+	}
+}
@@ -1,275 +1,218 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
-	"strconv"
+	"os"
 	"strings"
 )
 
 func main() {
-	var stateStr string
-	flag.StringVar(&stateStr, "state", "", "the initial sudoku state")
+	var stateStr, variant, regionsPath, format string
+	var size, workers int
+	var all bool
+	flag.StringVar(&stateStr, "state", "", "the initial sudoku state (a file path when -format sdm)")
+	flag.StringVar(&variant, "variant", "standard", "puzzle variant: standard|diagonal|hyper|jigsaw")
+	flag.StringVar(&regionsPath, "regions", "", "path to a JSON file of regions, required for -variant jigsaw")
+	flag.StringVar(&format, "format", "csv", "state format: csv|line|grid|sdm")
+	flag.IntVar(&size, "size", 9, "the side length of the grid (4, 9, 16, ...)")
+	flag.IntVar(&workers, "workers", 0, "number of search goroutines to use (defaults to runtime.NumCPU())")
+	flag.BoolVar(&all, "all", false, "collect every solution instead of stopping at the first")
 	flag.Parse()
 
+	if format == "sdm" {
+		solveSDM(stateStr, size)
+		return
+	}
+
 	// build initial state
-	initialState, err := getInitialState(stateStr)
+	cells, err := getInitialState(stateStr, size, format)
 	if err != nil {
 		panic(err)
 	}
 
-	fmt.Println("Initial State is valid")
-
-	printState(initialState)
-
-	iterations := 0
-
-	stack := [][]uint8{initialState}
+	puzzle, err := buildPuzzle(variant, size, cells, regionsPath)
+	if err != nil {
+		panic(err)
+	}
 
-	for len(stack) > 0 {
-		iterations++
+	fmt.Println("Initial State is valid")
 
-		state := stack[0]
-		stack = stack[1:]
+	printState(puzzle)
 
-		if isComplete(state) {
-			fmt.Println("Found a solution")
-			printState(state)
-			break
+	if variant == "standard" && size == dlxSize && !all {
+		deduced := Deduce(cells)
+		if len(deduced.Techniques) > 0 {
+			fmt.Printf("Techniques used: %s\n", strings.Join(deduced.Techniques, ", "))
+			fmt.Printf("Difficulty: %s\n", deduced.Difficulty)
 		}
 
-		expandedStates := expand(state)
-
-		stack = append(expandedStates, stack...)
-	}
-
-	fmt.Println("Done")
-}
-
-// getInitialState parses a string into a sudoku state
-func getInitialState(state string) ([]uint8, error) {
-	cells := strings.Split(state, ",")
-	if len(cells) != 81 {
-		return nil, errors.New("could not parse state")
-	}
-
-	var initialState []uint8
-	for i := 0; i < 81; i++ {
-		val, err := strconv.ParseInt(cells[i], 10, 8)
+		solved, err := SolveDLX(deduced.State)
 		if err != nil {
-			return nil, errors.New("could not parse cell")
+			panic(err)
 		}
 
-		initialState = append(initialState, uint8(val))
+		fmt.Println("Found a solution")
+		printState(&Puzzle{Size: size, Cells: solved})
+		fmt.Println("Done")
+		return
 	}
 
-	return initialState, nil
-}
-
-func printState(state []uint8) error {
-	if len(state) != 81 {
-		return errors.New("state is not the right size")
-	}
+	solutions, iterations := SolveParallel(puzzle, workers, all)
 
-	// top edge
-	printMajorHorizontalBoundary()
-	for i := 0; i < 9; i++ {
-		// each row
-		printRow(state, i)
-	}
-
-	return nil
-}
-
-func printHorizontalBoundary() {
-	fmt.Println("-------------------")
-}
-
-func printMajorHorizontalBoundary() {
-	fmt.Println("===================")
-}
-
-func printRow(state []uint8, i int) {
-	start := i * 9
-	end := i*9 + 9
-	r := state[start:end]
-	row := getFormattedCells(r)
-	fmt.Printf("\u2016%s|%s|%s\u2016%s|%s|%s\u2016%s|%s|%s\u2016\n", row[0], row[1], row[2], row[3], row[4], row[5], row[6], row[7], row[8])
-
-	rem := i % 3
-	if rem == 2 {
-		printMajorHorizontalBoundary()
+	if len(solutions) == 0 {
+		fmt.Println("No solution found")
+	} else if all {
+		fmt.Printf("Found %d solution(s)\n", len(solutions))
+		for _, s := range solutions {
+			printState(s)
+		}
 	} else {
-		printHorizontalBoundary()
+		fmt.Println("Found a solution")
+		printState(solutions[0])
 	}
-}
 
-func getFormattedCells(row []uint8) []string {
-	var formatted []string
+	fmt.Printf("Done (%d iterations)\n", iterations)
+}
 
-	for i := 0; i < 9; i++ {
-		if cell := row[i]; cell == 0 {
-			formatted = append(formatted, " ")
-		} else {
-			formatted = append(formatted, fmt.Sprintf("%d", cell))
-		}
+// solveSDM batch-solves an SDM file (one standard 9x9 puzzle per
+// line), printing each solved line in turn.
+func solveSDM(path string, size int) {
+	puzzles, err := readSDM(path, size)
+	if err != nil {
+		panic(err)
 	}
 
-	return formatted
-}
-
-func expand(state []uint8) [][]uint8 {
-	expandIdx := getExpandIndex(state)
-	return expandOn(state, expandIdx)
-}
+	for i, cells := range puzzles {
+		deduced := Deduce(cells)
 
-// getExpandIndex finds the next index upon which to expand
-// it looks for the index with the lowest branching factor
-func getExpandIndex(state []uint8) int {
-	lowestBranchingFactor := 10 // default value larger than any possible branching factor
-	bestIndex := 0
-
-	for idx, val := range state {
-		if val == 0 {
-			if branches := getPossibleValues(state, idx); len(branches) < lowestBranchingFactor {
-				lowestBranchingFactor = len(branches)
-				bestIndex = idx
-			}
+		solved, err := SolveDLX(deduced.State)
+		if err != nil {
+			fmt.Printf("%d: no solution found\n", i)
+			continue
 		}
 
-		// we can immediately fill in this value!
-		if lowestBranchingFactor == 1 {
-			break
+		line, err := formatState(solved, "line")
+		if err != nil {
+			panic(err)
 		}
-	}
-
-	return bestIndex
-}
-
-func expandOn(state []uint8, idx int) [][]uint8 {
-	var expandedStates [][]uint8
-
-	expandValues := getPossibleValues(state, idx)
 
-	for _, v := range expandValues {
-		expandedState := make([]uint8, 81)
-		copy(expandedState, state)
-		expandedState[idx] = v
-		expandedStates = append(expandedStates, expandedState)
+		fmt.Println(line)
 	}
-	return expandedStates
 }
 
-// simple completion check assuming the state is valid
-func isComplete(state []uint8) bool {
-	for _, val := range state {
-		if val == 0 {
-			return false
+// buildPuzzle constructs a Puzzle for the requested variant.
+func buildPuzzle(variant string, size int, cells []uint8, regionsPath string) (*Puzzle, error) {
+	switch variant {
+	case "standard":
+		return NewStandardPuzzle(size, cells)
+	case "diagonal":
+		return NewDiagonalPuzzle(size, cells)
+	case "hyper":
+		return NewHyperPuzzle(cells)
+	case "jigsaw":
+		regions, err := loadRegions(regionsPath)
+		if err != nil {
+			return nil, err
 		}
+		return NewJigsawPuzzle(size, cells, regions)
+	default:
+		return nil, fmt.Errorf("unknown variant %q", variant)
 	}
-
-	return true
 }
 
-func getPossibleValues(state []uint8, idx int) []uint8 {
-	// all possible values
-	values := []uint8{1, 2, 3, 4, 5, 6, 7, 8, 9}
+// loadRegions reads a JSON file containing one array of cell indices
+// per jigsaw region, e.g. [[0,1,9,10], [2,3,11,12], ...].
+func loadRegions(path string) ([][]int, error) {
+	if path == "" {
+		return nil, errors.New("-regions is required for -variant jigsaw")
+	}
 
-	// get square neighbours
-	squareNeighbours := getSquareNeighbours(state, idx)
-	// get row neighbours
-	rowNeighbours := getRowNeighbours(state, idx)
-	// get column neighbours
-	columnNeighbours := getColumnNeighbours(state, idx)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
 
-	values = removeFromSlice(values, squareNeighbours)
-	values = removeFromSlice(values, rowNeighbours)
-	values = removeFromSlice(values, columnNeighbours)
+	var regions [][]int
+	if err := json.Unmarshal(data, &regions); err != nil {
+		return nil, errors.New("could not parse regions file")
+	}
 
-	return values
+	return regions, nil
 }
 
-func removeFromSlice(s []uint8, rem []uint8) []uint8 {
-	var t []uint8
+func printState(p *Puzzle) error {
+	if len(p.Cells) != p.Size*p.Size {
+		return errors.New("state is not the right size")
+	}
 
-	for _, n := range s {
-		found := false
-		for _, r := range rem {
-			if r == n {
-				found = true
-				break
-			}
+	boxSize, err := boxSizeFor(p.Size)
+	if err != nil {
+		// sizes that are not perfect squares (possible for jigsaw grids)
+		// have no standard boxes and fall back to a plain row dump; a
+		// jigsaw grid of square size is drawn with the standard box lines
+		for i := 0; i < p.Size; i++ {
+			printRow(p, i, 0)
 		}
+		return nil
+	}
 
-		if !found {
-			t = append(t, n)
-		}
+	printMajorHorizontalBoundary(p.Size)
+	for i := 0; i < p.Size; i++ {
+		printRow(p, i, boxSize)
 	}
 
-	return t
+	return nil
 }
 
-func getSquareNeighbours(state []uint8, idx int) []uint8 {
-	var neighbours []uint8
-
-	// determine cell column
-	cellCol := idx % 3
-
-	// determine cell row
-	cellRow := (idx / 9) % 3
-
-	// determine top left corner of cell
-	cellStart := idx - cellRow*9 - cellCol
-
-	// iterate over rows
-	for i := 0; i < 3; i++ {
-		// iterate over cols
-		for j := 0; j < 3; j++ {
-			curr := cellStart + 9*i + j
-			if curr != idx {
-				if val := state[curr]; val != 0 {
-					neighbours = append(neighbours, val)
-				}
-			}
-		}
-	}
-
-	return neighbours
+func printHorizontalBoundary(size int) {
+	fmt.Println(strings.Repeat("-", size*2+1))
 }
 
-func getRowNeighbours(state []uint8, idx int) []uint8 {
-	var neighbours []uint8
+func printMajorHorizontalBoundary(size int) {
+	fmt.Println(strings.Repeat("=", size*2+1))
+}
 
-	// determine row
-	row := idx / 9
+func printRow(p *Puzzle, i int, boxSize int) {
+	start := i * p.Size
+	end := start + p.Size
+	row := getFormattedCells(p.Cells[start:end])
 
-	for i := 0; i < 9; i++ {
-		curr := 9*row + i
-		if curr != idx {
-			if val := state[curr]; val != 0 {
-				neighbours = append(neighbours, val)
-			}
+	var sb strings.Builder
+	sb.WriteString("‖")
+	for col, cell := range row {
+		sb.WriteString(cell)
+		if col == len(row)-1 {
+			continue
+		}
+		if boxSize > 0 && col%boxSize == boxSize-1 {
+			sb.WriteString("‖")
+		} else {
+			sb.WriteString("|")
 		}
 	}
+	sb.WriteString("‖")
+	fmt.Println(sb.String())
 
-	return neighbours
+	if boxSize > 0 && i%boxSize == boxSize-1 {
+		printMajorHorizontalBoundary(p.Size)
+	} else {
+		printHorizontalBoundary(p.Size)
+	}
 }
 
-func getColumnNeighbours(state []uint8, idx int) []uint8 {
-	var neighbours []uint8
-
-	// determine column
-	col := idx % 9
+func getFormattedCells(row []uint8) []string {
+	formatted := make([]string, 0, len(row))
 
-	for i := 0; i < 9; i++ {
-		curr := i*9 + col
-		if curr != idx {
-			if val := state[curr]; val != 0 {
-				neighbours = append(neighbours, val)
-			}
+	for _, cell := range row {
+		if cell == 0 {
+			formatted = append(formatted, " ")
+		} else {
+			formatted = append(formatted, fmt.Sprintf("%d", cell))
 		}
 	}
 
-	return neighbours
+	return formatted
 }
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// SolveParallel fans the first level of root's search tree out across
+// a worker pool (size workers, or runtime.NumCPU() if workers <= 0),
+// with each worker then running its own sequential DFS over a
+// sub-branch. As soon as one worker finds a complete solution it
+// cancels the others, unless all is true, in which case every solution
+// is collected instead -- handy for checking a puzzle has exactly one
+// solution.
+func SolveParallel(root *Puzzle, workers int, all bool) (solutions []*Puzzle, iterations int64) {
+	if isStandard9(root) {
+		return solveParallelBitmask(root, workers, all)
+	}
+
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	branches := expand(root)
+	if len(branches) == 0 {
+		branches = []*Puzzle{root}
+	}
+
+	jobs := make(chan *Puzzle, len(branches))
+	for _, b := range branches {
+		jobs <- b
+	}
+	close(jobs)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for branch := range jobs {
+				found := dfs(ctx, branch, &iterations, all)
+				if len(found) == 0 {
+					continue
+				}
+
+				mu.Lock()
+				if all || len(solutions) == 0 {
+					solutions = append(solutions, found...)
+				}
+				mu.Unlock()
+
+				if !all {
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return solutions, atomic.LoadInt64(&iterations)
+}
+
+// dfs runs the original sequential stack-based search over a single
+// sub-branch, returning early once ctx is cancelled by a sibling
+// worker. When all is false it stops at the first solution found.
+func dfs(ctx context.Context, start *Puzzle, iterations *int64, all bool) []*Puzzle {
+	var found []*Puzzle
+	stack := []*Puzzle{start}
+
+	for len(stack) > 0 {
+		select {
+		case <-ctx.Done():
+			return found
+		default:
+		}
+
+		atomic.AddInt64(iterations, 1)
+
+		p := stack[0]
+		stack = stack[1:]
+
+		if p.isComplete() {
+			found = append(found, p)
+			if !all {
+				return found
+			}
+			continue
+		}
+
+		stack = append(expand(p), stack...)
+	}
+
+	return found
+}
@@ -0,0 +1,76 @@
+package main
+
+// Constraint is a set of cell indices that must all hold distinct
+// non-zero values. Rows, columns, boxes, diagonals and jigsaw regions
+// are all expressed as Constraints.
+type Constraint interface {
+	// Indices returns the cell indices covered by this constraint.
+	Indices() []int
+}
+
+// Unique is a Constraint requiring every cell in indices to contain a
+// different value, mirroring the "Unique" constraint used by the
+// vimagination.zapto.org solver.
+type Unique struct {
+	indices []int
+}
+
+// NewUnique builds a Unique constraint over the given cell indices.
+func NewUnique(indices ...int) Unique {
+	return Unique{indices: indices}
+}
+
+// Indices returns the cell indices covered by this constraint.
+func (u Unique) Indices() []int {
+	return u.indices
+}
+
+// Puzzle describes a Sudoku-like grid: its side length, the current
+// cell values (row-major, 0 meaning blank), and the constraints that
+// must hold once the grid is solved.
+type Puzzle struct {
+	Size        int
+	Cells       []uint8
+	Constraints []Constraint
+}
+
+// clone returns a Puzzle with a copy of Cells, sharing the (immutable)
+// Constraints slice with the receiver.
+func (p *Puzzle) clone() *Puzzle {
+	cells := make([]uint8, len(p.Cells))
+	copy(cells, p.Cells)
+
+	return &Puzzle{
+		Size:        p.Size,
+		Cells:       cells,
+		Constraints: p.Constraints,
+	}
+}
+
+// isComplete reports whether every cell in the puzzle has been filled
+// in. It assumes the state is valid.
+func (p *Puzzle) isComplete() bool {
+	for _, val := range p.Cells {
+		if val == 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+// constraintsOn returns the constraints that cover the given cell index.
+func (p *Puzzle) constraintsOn(idx int) []Constraint {
+	var matched []Constraint
+
+	for _, c := range p.Constraints {
+		for _, i := range c.Indices() {
+			if i == idx {
+				matched = append(matched, c)
+				break
+			}
+		}
+	}
+
+	return matched
+}
@@ -0,0 +1,95 @@
+package main
+
+// expand picks the best cell to branch on and returns one candidate
+// Puzzle per value it could legally take.
+func expand(p *Puzzle) []*Puzzle {
+	expandIdx := getExpandIndex(p)
+	return expandOn(p, expandIdx)
+}
+
+// getExpandIndex finds the next index upon which to expand. It looks
+// for the blank cell with the lowest branching factor.
+func getExpandIndex(p *Puzzle) int {
+	lowestBranchingFactor := p.Size + 1 // default value larger than any possible branching factor
+	bestIndex := 0
+
+	for idx, val := range p.Cells {
+		if val == 0 {
+			if branches := getPossibleValues(p, idx); len(branches) < lowestBranchingFactor {
+				lowestBranchingFactor = len(branches)
+				bestIndex = idx
+			}
+		}
+
+		// we can immediately fill in this value!
+		if lowestBranchingFactor == 1 {
+			break
+		}
+	}
+
+	return bestIndex
+}
+
+func expandOn(p *Puzzle, idx int) []*Puzzle {
+	var expandedPuzzles []*Puzzle
+
+	for _, v := range getPossibleValues(p, idx) {
+		expanded := p.clone()
+		expanded.Cells[idx] = v
+		expandedPuzzles = append(expandedPuzzles, expanded)
+	}
+
+	return expandedPuzzles
+}
+
+// getPossibleValues returns the values idx could legally take given
+// every constraint that covers it.
+func getPossibleValues(p *Puzzle, idx int) []uint8 {
+	values := make([]uint8, p.Size)
+	for i := range values {
+		values[i] = uint8(i + 1)
+	}
+
+	for _, c := range p.constraintsOn(idx) {
+		values = removeFromSlice(values, neighbourValues(p, c, idx))
+	}
+
+	return values
+}
+
+// neighbourValues returns the values already placed in a constraint's
+// cells, excluding idx itself.
+func neighbourValues(p *Puzzle, c Constraint, idx int) []uint8 {
+	var values []uint8
+
+	for _, i := range c.Indices() {
+		if i == idx {
+			continue
+		}
+		if val := p.Cells[i]; val != 0 {
+			values = append(values, val)
+		}
+	}
+
+	return values
+}
+
+func removeFromSlice(s []uint8, rem []uint8) []uint8 {
+	var t []uint8
+
+	for _, n := range s {
+		found := false
+		for _, r := range rem {
+			if r == n {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			t = append(t, n)
+		}
+	}
+
+	return t
+}
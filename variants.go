@@ -0,0 +1,190 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// NewStandardPuzzle builds the row, column and box constraints for a
+// standard NxN Sudoku grid. size must be a perfect square (4, 9, 16, ...).
+func NewStandardPuzzle(size int, cells []uint8) (*Puzzle, error) {
+	boxSize, err := boxSizeFor(size)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cells) != size*size {
+		return nil, errors.New("cells does not match puzzle size")
+	}
+
+	p := &Puzzle{Size: size, Cells: cells}
+	p.Constraints = append(p.Constraints, rowConstraints(size)...)
+	p.Constraints = append(p.Constraints, columnConstraints(size)...)
+	p.Constraints = append(p.Constraints, boxConstraints(size, boxSize)...)
+
+	return p, nil
+}
+
+// NewDiagonalPuzzle builds a standard puzzle plus the two main
+// diagonals, i.e. an X-Sudoku.
+func NewDiagonalPuzzle(size int, cells []uint8) (*Puzzle, error) {
+	p, err := NewStandardPuzzle(size, cells)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Constraints = append(p.Constraints, diagonalConstraints(size)...)
+
+	return p, nil
+}
+
+// NewHyperPuzzle builds a standard 9x9 puzzle plus the four extra 3x3
+// "hyper" regions used by Hypersudoku.
+func NewHyperPuzzle(cells []uint8) (*Puzzle, error) {
+	p, err := NewStandardPuzzle(9, cells)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Constraints = append(p.Constraints, hyperConstraints()...)
+
+	return p, nil
+}
+
+// NewJigsawPuzzle builds row and column constraints plus the
+// user-supplied irregular regions in place of boxes. regions must
+// partition every cell index in [0, size*size) exactly once; any gap,
+// overlap or out-of-range index is rejected rather than left to panic
+// later when a constraint is evaluated against Cells.
+func NewJigsawPuzzle(size int, cells []uint8, regions [][]int) (*Puzzle, error) {
+	if len(cells) != size*size {
+		return nil, errors.New("cells does not match puzzle size")
+	}
+
+	if len(regions) != size {
+		return nil, errors.New("jigsaw puzzle requires size regions")
+	}
+
+	seen := make([]bool, size*size)
+	for _, region := range regions {
+		if len(region) != size {
+			return nil, errors.New("jigsaw region does not match puzzle size")
+		}
+		for _, idx := range region {
+			if idx < 0 || idx >= size*size {
+				return nil, fmt.Errorf("jigsaw region index %d out of range for size %d", idx, size)
+			}
+			if seen[idx] {
+				return nil, fmt.Errorf("jigsaw region index %d appears in more than one region", idx)
+			}
+			seen[idx] = true
+		}
+	}
+	for idx, ok := range seen {
+		if !ok {
+			return nil, fmt.Errorf("jigsaw regions do not cover cell %d", idx)
+		}
+	}
+
+	p := &Puzzle{Size: size, Cells: cells}
+	p.Constraints = append(p.Constraints, rowConstraints(size)...)
+	p.Constraints = append(p.Constraints, columnConstraints(size)...)
+
+	for _, region := range regions {
+		p.Constraints = append(p.Constraints, NewUnique(region...))
+	}
+
+	return p, nil
+}
+
+// boxSizeFor returns the side length of a standard box (e.g. 3 for a
+// 9x9 grid) and errors if size is not a perfect square.
+func boxSizeFor(size int) (int, error) {
+	boxSize := int(math.Sqrt(float64(size)))
+	if boxSize*boxSize != size {
+		return 0, errors.New("size must be a perfect square")
+	}
+
+	return boxSize, nil
+}
+
+func rowConstraints(size int) []Constraint {
+	constraints := make([]Constraint, 0, size)
+
+	for row := 0; row < size; row++ {
+		indices := make([]int, size)
+		for col := 0; col < size; col++ {
+			indices[col] = row*size + col
+		}
+		constraints = append(constraints, NewUnique(indices...))
+	}
+
+	return constraints
+}
+
+func columnConstraints(size int) []Constraint {
+	constraints := make([]Constraint, 0, size)
+
+	for col := 0; col < size; col++ {
+		indices := make([]int, size)
+		for row := 0; row < size; row++ {
+			indices[row] = row*size + col
+		}
+		constraints = append(constraints, NewUnique(indices...))
+	}
+
+	return constraints
+}
+
+func boxConstraints(size, boxSize int) []Constraint {
+	constraints := make([]Constraint, 0, size)
+
+	for boxRow := 0; boxRow < boxSize; boxRow++ {
+		for boxCol := 0; boxCol < boxSize; boxCol++ {
+			indices := make([]int, 0, size)
+			for i := 0; i < boxSize; i++ {
+				for j := 0; j < boxSize; j++ {
+					row := boxRow*boxSize + i
+					col := boxCol*boxSize + j
+					indices = append(indices, row*size+col)
+				}
+			}
+			constraints = append(constraints, NewUnique(indices...))
+		}
+	}
+
+	return constraints
+}
+
+func diagonalConstraints(size int) []Constraint {
+	main := make([]int, size)
+	anti := make([]int, size)
+
+	for i := 0; i < size; i++ {
+		main[i] = i*size + i
+		anti[i] = i*size + (size - 1 - i)
+	}
+
+	return []Constraint{NewUnique(main...), NewUnique(anti...)}
+}
+
+func hyperConstraints() []Constraint {
+	// the four 3x3 regions offset by one cell from the standard boxes
+	starts := [][2]int{{1, 1}, {1, 5}, {5, 1}, {5, 5}}
+	constraints := make([]Constraint, 0, len(starts))
+
+	for _, start := range starts {
+		indices := make([]int, 0, 9)
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				row := start[0] + i
+				col := start[1] + j
+				indices = append(indices, row*9+col)
+			}
+		}
+		constraints = append(constraints, NewUnique(indices...))
+	}
+
+	return constraints
+}
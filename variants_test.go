@@ -0,0 +1,141 @@
+package main
+
+import "testing"
+
+// standardBoxRegions returns the classic 3x3 box regions for a 9x9
+// grid, expressed as the [][]int shape NewJigsawPuzzle expects, so
+// jigsaw tests can reuse a region layout known to be a valid partition.
+func standardBoxRegions() [][]int {
+	regions := make([][]int, 0, 9)
+	for boxRow := 0; boxRow < 3; boxRow++ {
+		for boxCol := 0; boxCol < 3; boxCol++ {
+			region := make([]int, 0, 9)
+			for i := 0; i < 3; i++ {
+				for j := 0; j < 3; j++ {
+					row := boxRow*3 + i
+					col := boxCol*3 + j
+					region = append(region, row*9+col)
+				}
+			}
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+func TestNewStandardPuzzleSolves(t *testing.T) {
+	puzzle, err := NewStandardPuzzle(9, append([]uint8(nil), classicPuzzle...))
+	if err != nil {
+		t.Fatalf("NewStandardPuzzle: %v", err)
+	}
+
+	solved, err := SolveDLX(puzzle.Cells)
+	if err != nil {
+		t.Fatalf("SolveDLX: %v", err)
+	}
+
+	for i, want := range classicSolution {
+		if solved[i] != want {
+			t.Fatalf("cell %d = %d, want %d", i, solved[i], want)
+		}
+	}
+}
+
+func TestNewDiagonalPuzzleSolves(t *testing.T) {
+	cells := make([]uint8, 81)
+	puzzle, err := NewDiagonalPuzzle(9, cells)
+	if err != nil {
+		t.Fatalf("NewDiagonalPuzzle: %v", err)
+	}
+
+	solutions, _ := SolveParallel(puzzle, 2, false)
+	if len(solutions) != 1 {
+		t.Fatalf("got %d solutions, want 1", len(solutions))
+	}
+
+	for _, c := range diagonalConstraints(9) {
+		var seen [9]bool
+		for _, idx := range c.Indices() {
+			v := solutions[0].Cells[idx]
+			if v == 0 || seen[v-1] {
+				t.Fatalf("diagonal constraint violated at index %d: %v", idx, solutions[0].Cells)
+			}
+			seen[v-1] = true
+		}
+	}
+}
+
+func TestNewHyperPuzzleSolves(t *testing.T) {
+	cells := make([]uint8, 81)
+	puzzle, err := NewHyperPuzzle(cells)
+	if err != nil {
+		t.Fatalf("NewHyperPuzzle: %v", err)
+	}
+
+	solutions, _ := SolveParallel(puzzle, 2, false)
+	if len(solutions) != 1 {
+		t.Fatalf("got %d solutions, want 1", len(solutions))
+	}
+
+	for _, c := range hyperConstraints() {
+		var seen [9]bool
+		for _, idx := range c.Indices() {
+			v := solutions[0].Cells[idx]
+			if v == 0 || seen[v-1] {
+				t.Fatalf("hyper constraint violated at index %d: %v", idx, solutions[0].Cells)
+			}
+			seen[v-1] = true
+		}
+	}
+}
+
+func TestNewJigsawPuzzleSolves(t *testing.T) {
+	puzzle, err := NewJigsawPuzzle(9, append([]uint8(nil), classicPuzzle...), standardBoxRegions())
+	if err != nil {
+		t.Fatalf("NewJigsawPuzzle: %v", err)
+	}
+
+	// standardBoxRegions repeats the standard boxes in order, so
+	// isStandard9 accepts this puzzle and SolveParallel takes the board9
+	// fast path for it.
+	solutions, _ := SolveParallel(puzzle, 2, false)
+	if len(solutions) != 1 {
+		t.Fatalf("got %d solutions, want 1", len(solutions))
+	}
+
+	for i, want := range classicSolution {
+		if solutions[0].Cells[i] != want {
+			t.Fatalf("cell %d = %d, want %d", i, solutions[0].Cells[i], want)
+		}
+	}
+}
+
+// TestNewJigsawPuzzleRejectsOutOfRangeIndex guards against a past bug
+// where an out-of-range region index reached neighbourValues unchecked
+// and panicked; NewJigsawPuzzle must reject it up front instead.
+func TestNewJigsawPuzzleRejectsOutOfRangeIndex(t *testing.T) {
+	regions := standardBoxRegions()
+	regions[0][0] = 999
+
+	if _, err := NewJigsawPuzzle(9, make([]uint8, 81), regions); err == nil {
+		t.Fatal("expected an error for an out-of-range region index")
+	}
+}
+
+func TestNewJigsawPuzzleRejectsOverlappingRegions(t *testing.T) {
+	regions := standardBoxRegions()
+	regions[1][0] = regions[0][0]
+
+	if _, err := NewJigsawPuzzle(9, make([]uint8, 81), regions); err == nil {
+		t.Fatal("expected an error for overlapping region indices")
+	}
+}
+
+func TestNewJigsawPuzzleRejectsGapInCoverage(t *testing.T) {
+	regions := standardBoxRegions()
+	regions[0][0] = regions[0][1]
+
+	if _, err := NewJigsawPuzzle(9, make([]uint8, 81), regions); err == nil {
+		t.Fatal("expected an error when regions leave a cell uncovered")
+	}
+}